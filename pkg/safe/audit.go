@@ -0,0 +1,310 @@
+package safe
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Decision values recorded in an AuditRecord.
+const (
+	auditAllowed   = "allowed"
+	auditCancelled = "cancelled"
+	auditBlocked   = "blocked"
+)
+
+// sensitiveFlags are redacted out of the argv stored in an audit record.
+var sensitiveFlags = []string{"--token", "--password"}
+
+// AuditRecord is one JSON Lines entry in the audit log.
+type AuditRecord struct {
+	Timestamp      time.Time `json:"timestamp"`
+	User           string    `json:"user"`
+	KubectlVersion string    `json:"kubectlVersion"`
+	Context        string    `json:"context"`
+	Namespace      string    `json:"namespace"`
+	Argv           []string  `json:"argv"`
+	Decision       string    `json:"decision"`
+	ExitCode       int       `json:"exitCode"`
+	DurationMS     int64     `json:"durationMs"`
+}
+
+// clock abstracts time.Now so audit timestamps and durations can be
+// controlled in tests.
+type clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// auditClock is the clock used to stamp audit records; swapped out in tests.
+var auditClock clock = systemClock{}
+
+// recordAudit builds an AuditRecord for a guarded invocation and appends it
+// to the audit log resolved from args. Logging failures are reported to
+// stderr but never override the caller's own result, since a broken audit
+// log must not block real kubectl usage.
+func recordAudit(args []string, context, namespace, decision string, exitCode int, duration time.Duration) {
+	record := AuditRecord{
+		Timestamp:      auditClock.Now(),
+		User:           currentUsername(),
+		KubectlVersion: kubectlClientVersion(),
+		Context:        context,
+		Namespace:      namespace,
+		Argv:           redactArgv(args),
+		Decision:       decision,
+		ExitCode:       exitCode,
+		DurationMS:     duration.Milliseconds(),
+	}
+
+	if err := appendAuditRecord(auditFilePath(args), record); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write audit log entry: %v\n", err)
+	}
+}
+
+// appendAuditRecord writes record as one JSON line to the audit log at path,
+// creating the parent directory and file as needed.
+func appendAuditRecord(path string, record AuditRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	if _, err := f.Write(append(encoded, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// redactArgv returns a copy of args with the values of any sensitiveFlags
+// replaced, so secrets like --token never reach the audit log.
+func redactArgv(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+
+	for i := 0; i < len(redacted); i++ {
+		for _, flag := range sensitiveFlags {
+			if strings.HasPrefix(redacted[i], flag+"=") {
+				redacted[i] = flag + "=REDACTED"
+			} else if redacted[i] == flag && i+1 < len(redacted) {
+				redacted[i+1] = "REDACTED"
+			}
+		}
+	}
+	return redacted
+}
+
+// kubectlSafeOnlyFlags are flags kubectl-safe consumes itself and must
+// strip out of args before forwarding them to kubectl, which wouldn't
+// otherwise recognize them. These all take a value.
+var kubectlSafeOnlyFlags = []string{"--audit-file", "--max-silent-targets"}
+
+// kubectlSafeOnlyBoolFlags are like kubectlSafeOnlyFlags but take no value,
+// so stripping them must not also swallow the next argument.
+var kubectlSafeOnlyBoolFlags = []string{"--preview"}
+
+// stripKubectlSafeFlags removes kubectlSafeOnlyFlags/kubectlSafeOnlyBoolFlags
+// (and, for the former, their values) from args before they're forwarded to
+// kubectl.
+func stripKubectlSafeFlags(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		matched := false
+		for _, flag := range kubectlSafeOnlyFlags {
+			if strings.HasPrefix(arg, flag+"=") {
+				matched = true
+				break
+			}
+			if arg == flag {
+				i++ // also skip its value
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			for _, flag := range kubectlSafeOnlyBoolFlags {
+				if arg == flag {
+					matched = true
+					break
+				}
+			}
+		}
+		if matched {
+			continue
+		}
+
+		out = append(out, arg)
+	}
+	return out
+}
+
+// auditFilePath resolves the audit log path: --audit-file in args, then
+// KUBECTL_SAFE_AUDIT, then the default XDG state directory.
+func auditFilePath(args []string) string {
+	if v := extractFlagValue(args, "--audit-file", "--audit-file"); v != "<not specified>" {
+		return v
+	}
+	if v := os.Getenv("KUBECTL_SAFE_AUDIT"); v != "" {
+		return v
+	}
+	return defaultAuditFilePath()
+}
+
+// defaultAuditFilePath returns $XDG_STATE_HOME/kubectl-safe/audit.log,
+// falling back to ~/.local/state/kubectl-safe/audit.log.
+func defaultAuditFilePath() string {
+	if stateHome := os.Getenv("XDG_STATE_HOME"); stateHome != "" {
+		return filepath.Join(stateHome, "kubectl-safe", "audit.log")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".local", "state", "kubectl-safe", "audit.log")
+	}
+	return filepath.Join(home, ".local", "state", "kubectl-safe", "audit.log")
+}
+
+// currentUsername returns the OS user running kubectl-safe, or "<unknown>"
+// if it can't be determined.
+func currentUsername() string {
+	u, err := user.Current()
+	if err != nil {
+		return "<unknown>"
+	}
+	return u.Username
+}
+
+// kubectlClientVersion returns the kubectl client's gitVersion, or
+// "<unknown>" if kubectl can't be queried.
+func kubectlClientVersion() string {
+	output, err := exec.Command("kubectl", "version", "--client", "-o", "json").Output()
+	if err != nil {
+		return "<unknown>"
+	}
+
+	var parsed struct {
+		ClientVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"clientVersion"`
+	}
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return "<unknown>"
+	}
+	return parsed.ClientVersion.GitVersion
+}
+
+// exitCodeFromError extracts a process exit code from the error returned by
+// executeKubectl, defaulting to 1 for errors that aren't an *exec.ExitError.
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
+
+// runAudit implements `kubectl safe audit`, which tails and filters the
+// audit log written by recordAudit.
+func runAudit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	since := fs.String("since", "", "only show records at or after this RFC3339 timestamp")
+	context := fs.String("context", "", "only show records for this --context")
+	decision := fs.String("decision", "", "only show records with this decision: allowed, cancelled, or blocked")
+	auditFile := fs.String("audit-file", "", "path to the audit log (defaults to KUBECTL_SAFE_AUDIT or the XDG state dir)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := *auditFile
+	if path == "" {
+		path = auditFilePath(nil)
+	}
+
+	var sinceTime time.Time
+	if *since != "" {
+		parsed, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %w", *since, err)
+		}
+		sinceTime = parsed
+	}
+
+	records, err := readAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if !sinceTime.IsZero() && record.Timestamp.Before(sinceTime) {
+			continue
+		}
+		if *context != "" && record.Context != *context {
+			continue
+		}
+		if *decision != "" && record.Decision != *decision {
+			continue
+		}
+
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit record: %w", err)
+		}
+		fmt.Println(string(encoded))
+	}
+	return nil
+}
+
+// readAuditLog parses every JSON Lines entry in the audit log at path,
+// returning (nil, nil) if the file doesn't exist yet.
+func readAuditLog(path string) ([]AuditRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log line: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,165 @@
+package safe
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that always returns a fixed time, used so audit tests
+// don't depend on wall-clock time.
+type fakeClock struct {
+	now time.Time
+}
+
+func (f fakeClock) Now() time.Time { return f.now }
+
+func withFakeClock(t *testing.T, now time.Time) {
+	t.Helper()
+	previous := auditClock
+	auditClock = fakeClock{now: now}
+	t.Cleanup(func() { auditClock = previous })
+}
+
+func TestRecordAuditWritesJSONLine(t *testing.T) {
+	withFakeClock(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	args := []string{"delete", "pod", "mypod", "--context=prod", "--namespace=default", "--token=super-secret"}
+
+	t.Setenv("KUBECTL_SAFE_AUDIT", path)
+	recordAudit(args, "prod", "default", auditAllowed, 0, 2*time.Second)
+
+	records, err := readAuditLog(path)
+	if err != nil {
+		t.Fatalf("readAuditLog() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Context != "prod" {
+		t.Errorf("Context = %q, want %q", record.Context, "prod")
+	}
+	if record.Decision != auditAllowed {
+		t.Errorf("Decision = %q, want %q", record.Decision, auditAllowed)
+	}
+	if record.DurationMS != 2000 {
+		t.Errorf("DurationMS = %d, want 2000", record.DurationMS)
+	}
+	if !record.Timestamp.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Timestamp = %v, want fake clock time", record.Timestamp)
+	}
+
+	for _, arg := range record.Argv {
+		if arg == "super-secret" || arg == "--token=super-secret" {
+			t.Errorf("expected --token value to be redacted, got argv %v", record.Argv)
+		}
+	}
+}
+
+func TestRedactArgv(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "equals form",
+			args: []string{"delete", "pod", "--token=super-secret"},
+			want: []string{"delete", "pod", "--token=REDACTED"},
+		},
+		{
+			name: "separate value form",
+			args: []string{"delete", "pod", "--password", "hunter2"},
+			want: []string{"delete", "pod", "--password", "REDACTED"},
+		},
+		{
+			name: "no sensitive flags",
+			args: []string{"delete", "pod", "--context=prod"},
+			want: []string{"delete", "pod", "--context=prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactArgv(tt.args)
+			if len(got) != len(tt.want) {
+				t.Fatalf("redactArgv(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("redactArgv(%v)[%d] = %q, want %q", tt.args, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStripKubectlSafeFlags(t *testing.T) {
+	args := []string{"delete", "pod", "--audit-file", "/tmp/audit.log", "--context=prod"}
+	got := stripKubectlSafeFlags(args)
+	want := []string{"delete", "pod", "--context=prod"}
+
+	if len(got) != len(want) {
+		t.Fatalf("stripKubectlSafeFlags(%v) = %v, want %v", args, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("stripKubectlSafeFlags(%v)[%d] = %q, want %q", args, i, got[i], want[i])
+		}
+	}
+}
+
+func TestStripKubectlSafeFlagsPreview(t *testing.T) {
+	args := []string{"scale", "deployment/foo", "--replicas=2", "--context=staging", "--namespace=default", "--preview"}
+	got := stripKubectlSafeFlags(args)
+	want := []string{"scale", "deployment/foo", "--replicas=2", "--context=staging", "--namespace=default"}
+
+	if len(got) != len(want) {
+		t.Fatalf("stripKubectlSafeFlags(%v) = %v, want %v", args, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("stripKubectlSafeFlags(%v)[%d] = %q, want %q", args, i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadAuditLogMissingFileIsEmpty(t *testing.T) {
+	records, err := readAuditLog(filepath.Join(t.TempDir(), "does-not-exist.log"))
+	if err != nil {
+		t.Fatalf("readAuditLog() error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %d", len(records))
+	}
+}
+
+func TestRunAuditFiltersByDecisionAndContext(t *testing.T) {
+	withFakeClock(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	t.Setenv("KUBECTL_SAFE_AUDIT", path)
+
+	recordAudit([]string{"delete"}, "prod", "default", auditAllowed, 0, time.Second)
+	recordAudit([]string{"delete"}, "staging", "default", auditCancelled, 1, time.Second)
+
+	if err := runAudit([]string{"--decision", auditCancelled}); err != nil {
+		t.Fatalf("runAudit() error = %v", err)
+	}
+	if err := runAudit([]string{"--context", "prod"}); err != nil {
+		t.Fatalf("runAudit() error = %v", err)
+	}
+
+	records, err := readAuditLog(path)
+	if err != nil {
+		t.Fatalf("readAuditLog() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected both records still present in the log, got %d", len(records))
+	}
+}
@@ -0,0 +1,318 @@
+package safe
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxSilentTargets is how many affected resources BlastRadius will
+// report without requiring the user to retype the count to proceed.
+const defaultMaxSilentTargets = 5
+
+// maxTruncatedNames is how many resource names BlastRadius prints before
+// falling back to "...and N more".
+const maxTruncatedNames = 10
+
+// Report summarizes the resources a command would affect.
+type Report struct {
+	Kind     string
+	Names    []string
+	Count    int
+	Warnings []string
+}
+
+// commandRunner abstracts running kubectl so it can be mocked in tests.
+type commandRunner interface {
+	// Run streams kubectl's stdio to the parent process (the real
+	// pass-through execution path).
+	Run(args []string) error
+	// Output runs kubectl and returns its stdout, for reads like
+	// `kubectl get -o json`.
+	Output(args []string) ([]byte, error)
+}
+
+// execKubectlRunner is the commandRunner used outside of tests.
+type execKubectlRunner struct{}
+
+func (execKubectlRunner) Run(args []string) error {
+	cmd := exec.Command("kubectl", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+func (execKubectlRunner) Output(args []string) ([]byte, error) {
+	return exec.Command("kubectl", args...).Output()
+}
+
+// kubectlRunner is the commandRunner used to size up a blast radius and to
+// execute the final guarded command; swapped out in tests.
+var kubectlRunner commandRunner = execKubectlRunner{}
+
+// needsBlastRadiusCheck reports whether args is a command whose effect can
+// silently reach far more resources than the one named on the command line:
+// a delete with -l/--selector or --all, scale --replicas=0, drain, or
+// rollout restart.
+func needsBlastRadiusCheck(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "delete":
+		return extractFlagValue(args, "--selector", "-l") != "<not specified>" || slices.Contains(args, "--all")
+	case "scale":
+		return extractFlagValue(args, "--replicas", "--replicas") == "0"
+	case "drain":
+		return true
+	case "rollout":
+		return len(args) > 1 && args[1] == "restart"
+	default:
+		return false
+	}
+}
+
+// maxSilentTargets reads --max-silent-targets from args, defaulting to
+// defaultMaxSilentTargets when absent or unparsable.
+func maxSilentTargets(args []string) int {
+	raw := extractFlagValue(args, "--max-silent-targets", "--max-silent-targets")
+	if raw == "<not specified>" {
+		return defaultMaxSilentTargets
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return defaultMaxSilentTargets
+	}
+	return n
+}
+
+// contextNamespaceFlags carries the --context/--namespace of args over to a
+// derived kubectl invocation.
+func contextNamespaceFlags(args []string) []string {
+	var flags []string
+	if context := extractFlagValue(args, "--context", "-c"); context != "<not specified>" {
+		flags = append(flags, "--context", context)
+	}
+	if namespace := extractFlagValue(args, "--namespace", "-n"); namespace != "<not specified>" {
+		flags = append(flags, "--namespace", namespace)
+	}
+	return flags
+}
+
+// resourceList mirrors the subset of a `kubectl get <kind> -o json` response
+// we need: just enough metadata to name and count matched resources.
+type resourceList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+// workload mirrors the subset of a Deployment/StatefulSet we need to warn
+// about a scale-to-zero.
+type workload struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas int `json:"replicas"`
+	} `json:"spec"`
+}
+
+// BlastRadius sizes up how many resources args would affect before the user
+// is asked to confirm it, reusing extractFlagValue to carry over the same
+// selector/namespace/context kubectl-safe already parsed.
+func BlastRadius(args []string) (Report, error) {
+	switch args[0] {
+	case "delete":
+		return blastRadiusForDelete(args)
+	case "scale":
+		return blastRadiusForScale(args)
+	case "drain":
+		return blastRadiusForDrain(args)
+	case "rollout":
+		return blastRadiusForRolloutRestart(args)
+	default:
+		return Report{}, fmt.Errorf("blast radius analysis is not supported for %q", args[0])
+	}
+}
+
+func blastRadiusForDelete(args []string) (Report, error) {
+	resource := firstPositionalArg(args)
+	if resource == "" {
+		return Report{}, fmt.Errorf("no resource given to delete")
+	}
+
+	getArgs := []string{"get", resource, "-o", "json"}
+	if selector := extractFlagValue(args, "--selector", "-l"); selector != "<not specified>" {
+		getArgs = append(getArgs, "--selector", selector)
+	}
+	getArgs = append(getArgs, contextNamespaceFlags(args)...)
+
+	report, err := listResources(getArgs, resource)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if strings.EqualFold(extractFlagValue(args, "--namespace", "-n"), "kube-system") {
+		report.Warnings = append(report.Warnings, "target namespace is kube-system")
+	}
+	return report, nil
+}
+
+func blastRadiusForScale(args []string) (Report, error) {
+	resource := firstPositionalArg(args)
+	if resource == "" {
+		return Report{}, fmt.Errorf("no resource given to scale")
+	}
+
+	getArgs := append([]string{"get", resource, "-o", "json"}, contextNamespaceFlags(args)...)
+	output, err := kubectlRunner.Output(getArgs)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to size up blast radius: %w", err)
+	}
+
+	var w workload
+	if err := json.Unmarshal(output, &w); err != nil {
+		return Report{}, fmt.Errorf("failed to parse %s: %w", resource, err)
+	}
+
+	report := Report{Kind: resource, Names: []string{w.Metadata.Name}, Count: 1}
+	if w.Spec.Replicas > 1 {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("%s is being scaled from %d replicas to 0", resource, w.Spec.Replicas))
+	}
+	return report, nil
+}
+
+func blastRadiusForDrain(args []string) (Report, error) {
+	node := firstPositionalArg(args)
+	if node == "" {
+		return Report{}, fmt.Errorf("no node given to drain")
+	}
+
+	getArgs := []string{"get", "pods", "--all-namespaces", "--field-selector", "spec.nodeName=" + node, "-o", "json"}
+	if context := extractFlagValue(args, "--context", "-c"); context != "<not specified>" {
+		getArgs = append(getArgs, "--context", context)
+	}
+
+	return listPods(getArgs)
+}
+
+func blastRadiusForRolloutRestart(args []string) (Report, error) {
+	// args is ["rollout", "restart", ...]; treat "restart" as the verb so
+	// flags preceding the resource (e.g. "rollout restart --context=prod
+	// deployment/web") are skipped instead of misread as the resource.
+	resource := firstPositionalArg(args[1:])
+	if resource == "" {
+		return Report{}, fmt.Errorf("no resource given to rollout restart")
+	}
+
+	getArgs := append([]string{"get", resource, "-o", "json"}, contextNamespaceFlags(args)...)
+	output, err := kubectlRunner.Output(getArgs)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to size up blast radius: %w", err)
+	}
+
+	var parsed struct {
+		Spec struct {
+			Selector struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			} `json:"selector"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return Report{}, fmt.Errorf("failed to parse %s: %w", resource, err)
+	}
+	if len(parsed.Spec.Selector.MatchLabels) == 0 {
+		return Report{Kind: resource, Names: []string{resource}, Count: 1}, nil
+	}
+
+	selector := make([]string, 0, len(parsed.Spec.Selector.MatchLabels))
+	for k, v := range parsed.Spec.Selector.MatchLabels {
+		selector = append(selector, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(selector)
+
+	getPodsArgs := append([]string{"get", "pods", "--selector", strings.Join(selector, ","), "-o", "json"}, contextNamespaceFlags(args)...)
+	return listPods(getPodsArgs)
+}
+
+// listPods runs getArgs through kubectlRunner and turns the resulting pod
+// list into a Report.
+func listPods(getArgs []string) (Report, error) {
+	return listResources(getArgs, "pods")
+}
+
+// listResources runs getArgs through kubectlRunner and turns the resulting
+// resource list into a Report labeled with kind.
+func listResources(getArgs []string, kind string) (Report, error) {
+	output, err := kubectlRunner.Output(getArgs)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to size up blast radius: %w", err)
+	}
+
+	var list resourceList
+	if err := json.Unmarshal(output, &list); err != nil {
+		return Report{}, fmt.Errorf("failed to parse %s list: %w", kind, err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.Metadata.Name)
+	}
+
+	return Report{Kind: kind, Names: names, Count: len(names)}, nil
+}
+
+// firstPositionalArg returns the first non-flag argument after the verb,
+// e.g. "deployment/web" out of ["scale", "deployment/web", "--replicas=0"].
+func firstPositionalArg(args []string) string {
+	positional := positionalArgs(args)
+	if len(positional) == 0 {
+		return ""
+	}
+	return positional[0]
+}
+
+// confirmBlastRadius prints the report and, once the count exceeds
+// maxTargets, requires the user to type the count back before proceeding.
+func confirmBlastRadius(reader *bufio.Reader, report Report, maxTargets int) error {
+	fmt.Printf("\nBlast radius: %d %s affected\n", report.Count, report.Kind)
+	for i, name := range report.Names {
+		if i >= maxTruncatedNames {
+			fmt.Printf("  ...and %d more\n", len(report.Names)-maxTruncatedNames)
+			break
+		}
+		fmt.Printf("  - %s\n", name)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	fmt.Println()
+
+	if report.Count <= maxTargets {
+		return nil
+	}
+
+	fmt.Printf("This affects more than %d resources. Type the count (%d) to proceed: ", maxTargets, report.Count)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read user input: %w", err)
+	}
+	if strings.TrimSpace(response) != strconv.Itoa(report.Count) {
+		return fmt.Errorf("operation cancelled: count did not match")
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package safe
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// fakeCommandRunner is a commandRunner stub that returns canned output
+// without shelling out to a real kubectl.
+type fakeCommandRunner struct {
+	output []byte
+	err    error
+
+	// runArgs records the args passed to the most recent Run call, for
+	// tests that care what was forwarded rather than just the outcome.
+	runArgs []string
+}
+
+func (f *fakeCommandRunner) Run(args []string) error {
+	f.runArgs = args
+	return f.err
+}
+
+func (f *fakeCommandRunner) Output([]string) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.output, nil
+}
+
+func withFakeRunner(t *testing.T, r commandRunner) {
+	t.Helper()
+	previous := kubectlRunner
+	kubectlRunner = r
+	t.Cleanup(func() { kubectlRunner = previous })
+}
+
+func TestNeedsBlastRadiusCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{"delete with selector", []string{"delete", "pods", "-l", "app=web"}, true},
+		{"delete with --all", []string{"delete", "pods", "--all"}, true},
+		{"delete single named pod", []string{"delete", "pod", "mypod"}, false},
+		{"scale to zero", []string{"scale", "deployment/web", "--replicas=0"}, true},
+		{"scale up", []string{"scale", "deployment/web", "--replicas=3"}, false},
+		{"drain", []string{"drain", "node-1"}, true},
+		{"rollout restart", []string{"rollout", "restart", "deployment/web"}, true},
+		{"rollout status", []string{"rollout", "status", "deployment/web"}, false},
+		{"apply", []string{"apply", "-f", "deployment.yaml"}, false},
+		{"empty", []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsBlastRadiusCheck(tt.args); got != tt.expected {
+				t.Errorf("needsBlastRadiusCheck(%v) = %v, want %v", tt.args, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMaxSilentTargets(t *testing.T) {
+	if got := maxSilentTargets([]string{"delete", "pods", "--all"}); got != defaultMaxSilentTargets {
+		t.Errorf("maxSilentTargets() = %d, want default %d", got, defaultMaxSilentTargets)
+	}
+	if got := maxSilentTargets([]string{"delete", "pods", "--all", "--max-silent-targets=2"}); got != 2 {
+		t.Errorf("maxSilentTargets() = %d, want 2", got)
+	}
+}
+
+func TestBlastRadiusForDeleteWithSelector(t *testing.T) {
+	withFakeRunner(t, &fakeCommandRunner{output: []byte(`{
+		"items": [
+			{"metadata": {"name": "web-1"}},
+			{"metadata": {"name": "web-2"}},
+			{"metadata": {"name": "web-3"}}
+		]
+	}`)})
+
+	report, err := BlastRadius([]string{"delete", "pods", "-l", "app=web", "--namespace", "default"})
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+	if report.Count != 3 {
+		t.Errorf("Count = %d, want 3", report.Count)
+	}
+	if len(report.Warnings) != 0 {
+		t.Errorf("expected no warnings for non-kube-system namespace, got %v", report.Warnings)
+	}
+}
+
+func TestBlastRadiusForDeleteWarnsOnKubeSystem(t *testing.T) {
+	withFakeRunner(t, &fakeCommandRunner{output: []byte(`{"items": [{"metadata": {"name": "coredns-1"}}]}`)})
+
+	report, err := BlastRadius([]string{"delete", "pods", "--all", "--namespace", "kube-system"})
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+	if len(report.Warnings) == 0 {
+		t.Error("expected a warning when targeting kube-system")
+	}
+}
+
+func TestBlastRadiusForDeleteUsesNamedResourceKind(t *testing.T) {
+	withFakeRunner(t, &fakeCommandRunner{output: []byte(`{
+		"items": [
+			{"metadata": {"name": "web"}},
+			{"metadata": {"name": "worker"}}
+		]
+	}`)})
+
+	report, err := BlastRadius([]string{"delete", "deployments", "--all", "--namespace", "default"})
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+	if report.Kind != "deployments" {
+		t.Errorf("Kind = %q, want %q", report.Kind, "deployments")
+	}
+	if report.Count != 2 {
+		t.Errorf("Count = %d, want 2", report.Count)
+	}
+}
+
+func TestBlastRadiusForRolloutRestartSkipsFlagsBeforeResource(t *testing.T) {
+	withFakeRunner(t, &fakeCommandRunner{output: []byte(`{"spec": {"selector": {"matchLabels": {"app": "web"}}}}`)})
+
+	report, err := BlastRadius([]string{"rollout", "restart", "--context=prod", "--namespace=default", "deployment/web"})
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+	if report.Kind != "pods" {
+		t.Errorf("Kind = %q, want %q", report.Kind, "pods")
+	}
+}
+
+func TestBlastRadiusForScaleWarnsOnMultipleReplicas(t *testing.T) {
+	withFakeRunner(t, &fakeCommandRunner{output: []byte(`{"metadata": {"name": "web"}, "spec": {"replicas": 3}}`)})
+
+	report, err := BlastRadius([]string{"scale", "deployment/web", "--replicas=0"})
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+	if report.Count != 1 {
+		t.Errorf("Count = %d, want 1", report.Count)
+	}
+	if len(report.Warnings) == 0 {
+		t.Error("expected a warning when scaling a multi-replica workload to zero")
+	}
+}
+
+func TestBlastRadiusForScaleNoWarningOnSingleReplica(t *testing.T) {
+	withFakeRunner(t, &fakeCommandRunner{output: []byte(`{"metadata": {"name": "web"}, "spec": {"replicas": 1}}`)})
+
+	report, err := BlastRadius([]string{"scale", "deployment/web", "--replicas=0"})
+	if err != nil {
+		t.Fatalf("BlastRadius() error = %v", err)
+	}
+	if len(report.Warnings) != 0 {
+		t.Errorf("expected no warning scaling a single-replica workload to zero, got %v", report.Warnings)
+	}
+}
+
+func TestConfirmBlastRadiusBelowThresholdSkipsPrompt(t *testing.T) {
+	report := Report{Kind: "pods", Names: []string{"web-1", "web-2"}, Count: 2}
+
+	// An empty reader would error if confirmBlastRadius tried to read from
+	// it, which is how we confirm the prompt was skipped.
+	reader := bufio.NewReader(strings.NewReader(""))
+	if err := confirmBlastRadius(reader, report, defaultMaxSilentTargets); err != nil {
+		t.Errorf("confirmBlastRadius() error = %v, want nil below threshold", err)
+	}
+}
+
+func TestConfirmBlastRadiusAboveThresholdRequiresTypedCount(t *testing.T) {
+	report := Report{Kind: "pods", Names: []string{"web-1", "web-2", "web-3"}, Count: 3}
+
+	mismatch := bufio.NewReader(strings.NewReader("2\n"))
+	if err := confirmBlastRadius(mismatch, report, 1); err == nil {
+		t.Error("expected an error when the typed count doesn't match")
+	}
+
+	match := bufio.NewReader(strings.NewReader("3\n"))
+	if err := confirmBlastRadius(match, report, 1); err != nil {
+		t.Errorf("confirmBlastRadius() error = %v, want nil when typed count matches", err)
+	}
+}
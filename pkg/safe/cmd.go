@@ -0,0 +1,127 @@
+package safe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// newRootCommand builds the kubectl-safe subcommand tree: guard, prompt,
+// audit, policy, dry-run, and plugin. It mirrors the pattern kubectl itself
+// uses for its plugin loader. Running with no subcommand, or one that
+// doesn't match any of the above (the common case - a bare kubectl verb
+// like "delete"), falls through to the same guarded dispatch as `guard`.
+func newRootCommand(policy *ProtectionPolicy) *cobra.Command {
+	root := &cobra.Command{
+		Use:                "safe",
+		Short:              "Interactive safety net for dangerous kubectl commands",
+		SilenceUsage:       true,
+		SilenceErrors:      true,
+		DisableFlagParsing: true,
+		// Cobra's default Args validator rejects positional args on a
+		// command that has subcommands, treating them as a mistyped
+		// subcommand name. That default would reject every bare
+		// `kubectl safe <verb>` invocation, so it's relaxed here to let
+		// an unrecognized first argument fall through to RunE below.
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dispatchKubectlCommand(args, policy)
+		},
+	}
+
+	root.AddCommand(newRunCommand(policy))
+	root.AddCommand(newPromptCommand())
+	root.AddCommand(newAuditCommand())
+	root.AddCommand(newPolicyCommand(policy))
+	root.AddCommand(newDryRunCommand())
+	root.AddCommand(newPluginCommand(policy))
+
+	return root
+}
+
+// newRunCommand is the explicit spelling of the guarded dispatch that the
+// root command falls back to for any unrecognized first argument. It's
+// named "guard" rather than "run" so it doesn't shadow kubectl's own `run`
+// verb - `kubectl safe run nginx --image=nginx` must still reach kubectl
+// as `run`, not be consumed as this subcommand's selector.
+func newRunCommand(policy *ProtectionPolicy) *cobra.Command {
+	return &cobra.Command{
+		Use:                "guard",
+		Short:              "Run a kubectl command through the safety guard",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dispatchKubectlCommand(args, policy)
+		},
+	}
+}
+
+// newPromptCommand wraps runPrompt for the subcommand tree.
+func newPromptCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:                "prompt",
+		Short:              "Print the active context/namespace for shell prompt integration",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPrompt(args)
+		},
+	}
+}
+
+// newAuditCommand wraps runAudit for the subcommand tree.
+func newAuditCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:                "audit",
+		Short:              "Tail and filter the audit log",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAudit(args)
+		},
+	}
+}
+
+// newPolicyCommand prints the effective protection policy, which is useful
+// for confirming what a ~/.kube/safe.yaml or $KUBECTL_SAFE_CONFIG actually
+// resolved to.
+func newPolicyCommand(policy *ProtectionPolicy) *cobra.Command {
+	return &cobra.Command{
+		Use:   "policy",
+		Short: "Print the effective protection policy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encoded, err := json.MarshalIndent(policy, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode protection policy: %w", err)
+			}
+			fmt.Println(string(encoded))
+			return nil
+		},
+	}
+}
+
+// newDryRunCommand runs a command with --dry-run=client appended, skipping
+// the confirmation flow entirely since a client-side dry run never mutates
+// the cluster.
+func newDryRunCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:                "dry-run",
+		Short:              "Run a command with --dry-run=client, skipping confirmation",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeKubectl(append(args, "--dry-run=client"))
+		},
+	}
+}
+
+// dispatchKubectlCommand is the guard entry point shared by the implicit
+// root command and the explicit `guard` subcommand: safe commands pass
+// straight through to kubectl, dangerous ones go through
+// executeDangerousCommand.
+func dispatchKubectlCommand(args []string, policy *ProtectionPolicy) error {
+	if len(args) == 0 {
+		return showUsage()
+	}
+	if !isDangerousCommand(args) {
+		return executeKubectl(args)
+	}
+	return executeDangerousCommand(args, policy)
+}
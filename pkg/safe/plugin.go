@@ -0,0 +1,133 @@
+package safe
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is the naming convention external safety-check plugins must
+// follow to be discovered on $PATH, e.g. kubectl-safe-opa, kubectl-safe-pdb.
+const pluginPrefix = "kubectl-safe-"
+
+// discoverPlugins finds external kubectl-safe-* executables on $PATH plus
+// any explicitly listed in policy.Plugins, keyed by the short name they are
+// exposed under (the executable name with the kubectl-safe- prefix removed).
+func discoverPlugins(policy *ProtectionPolicy) map[string]string {
+	plugins := map[string]string{}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), pluginPrefix) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.Mode()&0111 == 0 {
+				// Not executable - a README, a .bak, an editor swap file
+				// alongside a real plugin. Skip it rather than registering
+				// it and failing later with a confusing "permission denied"
+				// that reads like an intentional veto.
+				continue
+			}
+			name := strings.TrimPrefix(entry.Name(), pluginPrefix)
+			plugins[name] = filepath.Join(dir, entry.Name())
+		}
+	}
+
+	if policy != nil {
+		for _, path := range policy.Plugins {
+			resolved := path
+			if abs, err := exec.LookPath(path); err == nil {
+				resolved = abs
+			}
+			name := strings.TrimPrefix(filepath.Base(path), pluginPrefix)
+			plugins[name] = resolved
+		}
+	}
+
+	return plugins
+}
+
+// runPlugin invokes a discovered plugin executable, passing the guarded
+// command's context through a stable set of KUBECTL_SAFE_* environment
+// variables so third parties can add checks without modifying this repo. A
+// non-zero exit vetoes the command.
+func runPlugin(path string, args []string, context, namespace, decision string) error {
+	argvJSON, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to encode argv for plugin %s: %w", path, err)
+	}
+
+	cmd := exec.Command(path)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"KUBECTL_SAFE_CONTEXT="+context,
+		"KUBECTL_SAFE_NAMESPACE="+namespace,
+		"KUBECTL_SAFE_ARGV_JSON="+string(argvJSON),
+		"KUBECTL_SAFE_DECISION="+decision,
+	)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s vetoed the command: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// runGuardPlugins runs every discovered plugin as a veto gate ahead of
+// executeKubectl, returning the first error from a plugin that exits
+// non-zero.
+func runGuardPlugins(policy *ProtectionPolicy, args []string, context, namespace string) error {
+	for name, path := range discoverPlugins(policy) {
+		if err := runPlugin(path, args, context, namespace, "pending"); err != nil {
+			return fmt.Errorf("plugin %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// newPluginCommand builds the `kubectl safe plugin` subcommand: run with no
+// arguments to list discovered plugins, `kubectl safe plugin <name>
+// [args...]` to invoke one directly, or anything else (e.g. `list`) falls
+// through to the real `kubectl plugin` verb, since "plugin" is also a
+// built-in kubectl command and this must not swallow it. Discovery is done
+// lazily inside RunE, not at tree-build time, so commands that never touch
+// `plugin` don't pay for a $PATH scan.
+func newPluginCommand(policy *ProtectionPolicy) *cobra.Command {
+	return &cobra.Command{
+		Use:                "plugin",
+		Short:              "List or invoke discovered kubectl-safe-* plugins",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins := discoverPlugins(policy)
+
+			if len(args) == 0 {
+				if len(plugins) == 0 {
+					fmt.Println("No kubectl-safe-* plugins found on $PATH or in the config's plugins list.")
+					return nil
+				}
+				for name, path := range plugins {
+					fmt.Printf("%s\t%s\n", name, path)
+				}
+				return nil
+			}
+
+			if path, ok := plugins[args[0]]; ok {
+				return runPlugin(path, args[1:], "", "", "manual")
+			}
+
+			// args[0] isn't one of our own plugins - this is the real
+			// kubectl plugin verb (e.g. `kubectl safe plugin list`).
+			return executeKubectl(append([]string{"plugin"}, args...))
+		},
+	}
+}
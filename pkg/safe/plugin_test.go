@@ -0,0 +1,111 @@
+package safe
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeExecutable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("failed to write fake plugin %s: %v", path, err)
+	}
+}
+
+func TestDiscoverPluginsFromPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin discovery assumes unix-style executable bits")
+	}
+
+	dir := t.TempDir()
+	writeExecutable(t, filepath.Join(dir, "kubectl-safe-opa"))
+	writeExecutable(t, filepath.Join(dir, "kubectl-safe-pdb"))
+	writeExecutable(t, filepath.Join(dir, "kubectl"))
+
+	t.Setenv("PATH", dir)
+
+	plugins := discoverPlugins(defaultProtectionPolicy())
+
+	if _, ok := plugins["opa"]; !ok {
+		t.Errorf("expected kubectl-safe-opa to be discovered as %q, got %v", "opa", plugins)
+	}
+	if _, ok := plugins["pdb"]; !ok {
+		t.Errorf("expected kubectl-safe-pdb to be discovered as %q, got %v", "pdb", plugins)
+	}
+	if _, ok := plugins["kubectl"]; ok {
+		t.Errorf("did not expect plain kubectl to be discovered as a plugin, got %v", plugins)
+	}
+}
+
+func TestDiscoverPluginsFromPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin discovery assumes unix-style executable bits")
+	}
+
+	dir := t.TempDir()
+	pluginPath := filepath.Join(dir, "kubectl-safe-custom")
+	writeExecutable(t, pluginPath)
+
+	t.Setenv("PATH", "")
+
+	policy := &ProtectionPolicy{Plugins: []string{pluginPath}}
+	plugins := discoverPlugins(policy)
+
+	if got := plugins["custom"]; got != pluginPath {
+		t.Errorf("expected policy-listed plugin to resolve to %q, got %q", pluginPath, got)
+	}
+}
+
+func TestDispatchKubectlCommandNoArgsShowsUsage(t *testing.T) {
+	if err := dispatchKubectlCommand(nil, defaultProtectionPolicy()); err != nil {
+		t.Errorf("dispatchKubectlCommand(nil) error = %v, want nil (usage banner)", err)
+	}
+}
+
+func TestDiscoverPluginsSkipsNonExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin discovery assumes unix-style executable bits")
+	}
+
+	dir := t.TempDir()
+	writeExecutable(t, filepath.Join(dir, "kubectl-safe-opa"))
+	if err := os.WriteFile(filepath.Join(dir, "kubectl-safe-README.md"), []byte("docs"), 0o644); err != nil {
+		t.Fatalf("failed to write fake non-executable plugin file: %v", err)
+	}
+
+	t.Setenv("PATH", dir)
+
+	plugins := discoverPlugins(defaultProtectionPolicy())
+
+	if _, ok := plugins["opa"]; !ok {
+		t.Errorf("expected kubectl-safe-opa to be discovered, got %v", plugins)
+	}
+	if _, ok := plugins["README.md"]; ok {
+		t.Errorf("did not expect non-executable kubectl-safe-README.md to be discovered as a plugin, got %v", plugins)
+	}
+}
+
+func TestPluginCommandFallsThroughToKubectlPlugin(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	runner := &fakeCommandRunner{}
+	withFakeRunner(t, runner)
+
+	cmd := newPluginCommand(defaultProtectionPolicy())
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("plugin command execute error = %v", err)
+	}
+
+	want := []string{"plugin", "list"}
+	if len(runner.runArgs) != len(want) {
+		t.Fatalf("kubectl called with %v, want %v", runner.runArgs, want)
+	}
+	for i := range want {
+		if runner.runArgs[i] != want[i] {
+			t.Errorf("kubectl called with %v, want %v", runner.runArgs, want)
+		}
+	}
+}
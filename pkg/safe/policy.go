@@ -0,0 +1,127 @@
+package safe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProtectedContextPatterns are matched as case-insensitive substrings
+// against --context when no configuration file overrides them.
+var defaultProtectedContextPatterns = []string{"prod"}
+
+const (
+	confirmationTyped  = "typed"
+	confirmationSimple = "simple"
+)
+
+// ProtectionPolicy controls how strictly kubectl-safe guards a dangerous
+// command once --context has been resolved. It is loaded once per Execute
+// call and threaded through showConfirmation.
+type ProtectionPolicy struct {
+	// ProtectedContextPatterns are substrings (or, failing that, regular
+	// expressions) matched against --context. A match escalates
+	// showConfirmation from a yes/no prompt to a typed confirmation flow.
+	ProtectedContextPatterns []string `yaml:"protectedContextPatterns" json:"protectedContextPatterns"`
+
+	// RequireTypedNamespace additionally requires the user to retype
+	// --namespace (not just --context) once a typed confirmation triggers.
+	RequireTypedNamespace bool `yaml:"requireTypedNamespace" json:"requireTypedNamespace"`
+
+	// CommandOverrides forces "typed" or "simple" confirmation for a specific
+	// kubectl subcommand regardless of whether the context is protected, e.g.
+	// "delete" always typed, "scale" always a plain y/n.
+	CommandOverrides map[string]string `yaml:"commandOverrides" json:"commandOverrides"`
+
+	// Plugins lists additional kubectl-safe-* executables to load as guard
+	// plugins beyond whatever is discovered on $PATH - either bare names
+	// resolved against $PATH or absolute paths.
+	Plugins []string `yaml:"plugins" json:"plugins"`
+}
+
+// defaultProtectionPolicy is used when no configuration file is present:
+// only "prod"-like contexts are protected, "delete" always requires a typed
+// context and "scale" never does.
+func defaultProtectionPolicy() *ProtectionPolicy {
+	return &ProtectionPolicy{
+		ProtectedContextPatterns: append([]string{}, defaultProtectedContextPatterns...),
+		RequireTypedNamespace:    false,
+		CommandOverrides: map[string]string{
+			"delete": confirmationTyped,
+			"scale":  confirmationSimple,
+		},
+	}
+}
+
+// loadProtectionPolicy loads the protection policy from $KUBECTL_SAFE_CONFIG
+// or ~/.kube/safe.yaml, falling back to defaultProtectionPolicy when neither
+// exists. A malformed config file is a hard error so misconfiguration never
+// silently weakens the guard.
+func loadProtectionPolicy() (*ProtectionPolicy, error) {
+	path := os.Getenv("KUBECTL_SAFE_CONFIG")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return defaultProtectionPolicy(), nil
+		}
+		path = filepath.Join(home, ".kube", "safe.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaultProtectionPolicy(), nil
+		}
+		return nil, fmt.Errorf("failed to read protection policy %s: %w", path, err)
+	}
+
+	policy := defaultProtectionPolicy()
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse protection policy %s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+// isProtectedContext reports whether context matches one of the policy's
+// protected patterns. Patterns are tried as case-insensitive substrings
+// first, then as regular expressions, so a bare config value like "prod"
+// behaves intuitively while still allowing full regex patterns.
+func isProtectedContext(policy *ProtectionPolicy, context string) bool {
+	if policy == nil || context == "" {
+		return false
+	}
+
+	lowerContext := strings.ToLower(context)
+	for _, pattern := range policy.ProtectedContextPatterns {
+		if strings.Contains(lowerContext, strings.ToLower(pattern)) {
+			return true
+		}
+		if re, err := regexp.Compile(pattern); err == nil && re.MatchString(context) {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmationMode decides whether command against context requires a typed
+// confirmation or a simple yes/no, applying any per-command override before
+// falling back to the protected-context check.
+func confirmationMode(policy *ProtectionPolicy, command, context string) string {
+	if policy == nil {
+		return confirmationSimple
+	}
+
+	if override, ok := policy.CommandOverrides[command]; ok {
+		return override
+	}
+
+	if isProtectedContext(policy, context) {
+		return confirmationTyped
+	}
+	return confirmationSimple
+}
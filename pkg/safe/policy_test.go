@@ -0,0 +1,160 @@
+package safe
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestIsProtectedContext(t *testing.T) {
+	policy := defaultProtectionPolicy()
+
+	tests := []struct {
+		name     string
+		context  string
+		expected bool
+	}{
+		{
+			name:     "substring match",
+			context:  "us-east-prod",
+			expected: true,
+		},
+		{
+			name:     "case insensitive substring match",
+			context:  "PROD-cluster",
+			expected: true,
+		},
+		{
+			name:     "non-matching context",
+			context:  "staging",
+			expected: false,
+		},
+		{
+			name:     "empty context",
+			context:  "",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isProtectedContext(policy, tt.context)
+			if result != tt.expected {
+				t.Errorf("isProtectedContext(%v, %q) = %v, want %v", policy, tt.context, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsProtectedContextRegexPattern(t *testing.T) {
+	policy := &ProtectionPolicy{
+		ProtectedContextPatterns: []string{`^cluster-\d+-live$`},
+	}
+
+	if !isProtectedContext(policy, "cluster-42-live") {
+		t.Error("expected regex pattern to match cluster-42-live")
+	}
+	if isProtectedContext(policy, "cluster-live") {
+		t.Error("expected regex pattern not to match cluster-live")
+	}
+}
+
+func TestConfirmationMode(t *testing.T) {
+	policy := defaultProtectionPolicy()
+
+	tests := []struct {
+		name     string
+		command  string
+		context  string
+		expected string
+	}{
+		{
+			name:     "delete override forces typed even on safe context",
+			command:  "delete",
+			context:  "staging",
+			expected: confirmationTyped,
+		},
+		{
+			name:     "scale override forces simple even on prod context",
+			command:  "scale",
+			context:  "prod-cluster",
+			expected: confirmationSimple,
+		},
+		{
+			name:     "apply on prod context falls back to typed",
+			command:  "apply",
+			context:  "prod-cluster",
+			expected: confirmationTyped,
+		},
+		{
+			name:     "apply on safe context is simple",
+			command:  "apply",
+			context:  "staging",
+			expected: confirmationSimple,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := confirmationMode(policy, tt.command, tt.context)
+			if result != tt.expected {
+				t.Errorf("confirmationMode(policy, %q, %q) = %v, want %v", tt.command, tt.context, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRequireTypedConfirmationMismatch(t *testing.T) {
+	withFakeInteractiveStdin(t)
+
+	reader := bufio.NewReader(strings.NewReader("wrong-context\n"))
+	err := requireTypedConfirmation(reader, "prod-cluster", "default", false)
+	if err == nil {
+		t.Fatal("expected error when typed context does not match")
+	}
+}
+
+func TestRequireTypedConfirmationNonTTYRejected(t *testing.T) {
+	if isInteractiveStdin() {
+		t.Skip("test process stdin is a terminal; cannot exercise the non-TTY rejection path")
+	}
+
+	reader := bufio.NewReader(strings.NewReader("prod-cluster\n"))
+	err := requireTypedConfirmation(reader, "prod-cluster", "default", false)
+	if err == nil {
+		t.Fatal("expected non-interactive stdin to be rejected even with a matching typed value")
+	}
+	if !strings.Contains(err.Error(), "interactive terminal") {
+		t.Errorf("expected error to mention interactive terminal requirement, got: %v", err)
+	}
+}
+
+func withFakeInteractiveStdin(t *testing.T) {
+	t.Helper()
+	previous := isInteractiveStdin
+	isInteractiveStdin = func() bool { return true }
+	t.Cleanup(func() { isInteractiveStdin = previous })
+}
+
+func TestRequireTypedConfirmationNamespaceMismatch(t *testing.T) {
+	withFakeInteractiveStdin(t)
+
+	reader := bufio.NewReader(strings.NewReader("prod-cluster\nwrong-namespace\n"))
+	err := requireTypedConfirmation(reader, "prod-cluster", "default", true)
+	if err == nil {
+		t.Fatal("expected error when typed namespace does not match")
+	}
+	if !strings.Contains(err.Error(), "namespace") {
+		t.Errorf("expected error to mention the namespace mismatch, got: %v", err)
+	}
+}
+
+func TestRequireTypedConfirmationNamespaceMatch(t *testing.T) {
+	withFakeInteractiveStdin(t)
+
+	reader := bufio.NewReader(strings.NewReader("prod-cluster\ndefault\n"))
+	err := requireTypedConfirmation(reader, "prod-cluster", "default", true)
+	if err != nil {
+		t.Fatalf("expected matching typed context and namespace to succeed, got: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package safe
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// previewableCommands are dangerous commands for which a server-side dry run
+// produces a meaningful preview of the resulting object state.
+var previewableCommands = []string{"apply", "patch", "replace", "create", "delete"}
+
+// knownValueFlags are global flags that take a separate value, so they
+// (and that value) aren't mistaken for a positional resource/name argument.
+var knownValueFlags = map[string]bool{
+	"--context":   true,
+	"-c":          true,
+	"--namespace": true,
+	"-n":          true,
+	"--selector":  true,
+	"-l":          true,
+	"--filename":  true,
+	"-f":          true,
+}
+
+// previewEnabled reports whether the --preview flag or KUBECTL_SAFE_PREVIEW
+// env var opted this invocation into the server-side dry-run preview. It
+// costs an extra API round-trip, so it's opt-in rather than the default.
+func previewEnabled(args []string) bool {
+	if slices.Contains(args, "--preview") {
+		return true
+	}
+	return os.Getenv("KUBECTL_SAFE_PREVIEW") == "1"
+}
+
+// isPreviewableCommand reports whether args targets a command that a
+// server-side dry run can usefully preview.
+func isPreviewableCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	return slices.Contains(previewableCommands, args[0])
+}
+
+// showPreview renders a preview of what the command would do before the
+// user is asked to confirm it. For apply it shells out to `kubectl diff`,
+// which already knows how to compute a live vs. local diff. For the other
+// previewable commands it runs the same args with --dry-run=server -o yaml
+// and, when it can resolve the target's current state, prints a
+// field-by-field diff of current vs. proposed. It is best-effort: if the
+// server rejects the dry run, it falls back gracefully instead of blocking
+// confirmation.
+func showPreview(args []string) error {
+	fmt.Println("\n--- Server-side dry-run preview ---")
+
+	if args[0] == "apply" {
+		return showApplyDiff(args)
+	}
+
+	proposed, err := exec.Command("kubectl", append(stripKubectlSafeFlags(args), "--dry-run=server", "-o", "yaml")...).Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: server rejected the dry run, skipping preview: %v\n", err)
+		return nil
+	}
+
+	current, err := fetchCurrentObject(args)
+	if err != nil {
+		fmt.Print(string(proposed))
+		return nil
+	}
+
+	return printFieldDiff(current, proposed)
+}
+
+// showApplyDiff runs `kubectl diff` with the same flags as the apply call.
+func showApplyDiff(args []string) error {
+	diffArgs := append([]string{"diff"}, stripKubectlSafeFlags(args[1:])...)
+	cmd := exec.Command("kubectl", diffArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// kubectl diff exits 1 when it finds differences - that's the normal
+	// case here, not a failure worth surfacing.
+	_ = cmd.Run()
+	return nil
+}
+
+// fetchCurrentObject looks up the live object targeted by a previewable
+// command via `kubectl get <resource> <name> -o yaml`, reusing its
+// --context/--namespace. Returns an error if args doesn't have enough
+// positional resource/name arguments to build a `get` from (e.g. `apply -f`).
+func fetchCurrentObject(args []string) ([]byte, error) {
+	resourceArgs := positionalArgs(args)
+	if len(resourceArgs) == 0 {
+		return nil, fmt.Errorf("no resource/name arguments to look up the current state with")
+	}
+
+	getArgs := append([]string{"get"}, resourceArgs...)
+	getArgs = append(getArgs, "-o", "yaml")
+	if context := extractFlagValue(args, "--context", "-c"); context != "<not specified>" {
+		getArgs = append(getArgs, "--context", context)
+	}
+	if namespace := extractFlagValue(args, "--namespace", "-n"); namespace != "<not specified>" {
+		getArgs = append(getArgs, "--namespace", namespace)
+	}
+
+	return exec.Command("kubectl", getArgs...).Output()
+}
+
+// positionalArgs returns the verb's own arguments (args[1:]) with flags and
+// their values stripped out, leaving only positional resource/name tokens.
+func positionalArgs(args []string) []string {
+	var out []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if knownValueFlags[arg] {
+				i++
+			}
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// printFieldDiff parses currentYAML and proposedYAML into unstructured
+// objects and prints which fields were added, removed, or changed.
+func printFieldDiff(currentYAML, proposedYAML []byte) error {
+	var current, proposed unstructured.Unstructured
+	if err := yaml.Unmarshal(currentYAML, &current.Object); err != nil {
+		return fmt.Errorf("failed to parse current object: %w", err)
+	}
+	if err := yaml.Unmarshal(proposedYAML, &proposed.Object); err != nil {
+		return fmt.Errorf("failed to parse proposed object: %w", err)
+	}
+
+	diffMapFields("", current.Object, proposed.Object)
+	return nil
+}
+
+// diffMapFields recursively prints each field path that was added (green),
+// removed (red), or changed (yellow) between current and proposed.
+func diffMapFields(path string, current, proposed map[string]interface{}) {
+	keys := map[string]bool{}
+	for k := range current {
+		keys[k] = true
+	}
+	for k := range proposed {
+		keys[k] = true
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		currentValue, hasCurrent := current[key]
+		proposedValue, hasProposed := proposed[key]
+
+		switch {
+		case !hasCurrent:
+			color.New(color.FgGreen).Printf("+ %s: %v\n", fieldPath, proposedValue)
+		case !hasProposed:
+			color.New(color.FgRed).Printf("- %s: %v\n", fieldPath, currentValue)
+		default:
+			currentMap, currentIsMap := currentValue.(map[string]interface{})
+			proposedMap, proposedIsMap := proposedValue.(map[string]interface{})
+			if currentIsMap && proposedIsMap {
+				diffMapFields(fieldPath, currentMap, proposedMap)
+				continue
+			}
+			if !reflect.DeepEqual(currentValue, proposedValue) {
+				color.New(color.FgYellow).Printf("~ %s: %v -> %v\n", fieldPath, currentValue, proposedValue)
+			}
+		}
+	}
+}
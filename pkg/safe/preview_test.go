@@ -0,0 +1,88 @@
+package safe
+
+import (
+	"testing"
+)
+
+func TestPreviewEnabled(t *testing.T) {
+	if previewEnabled([]string{"apply", "-f", "deployment.yaml"}) {
+		t.Error("expected preview to be disabled without --preview or KUBECTL_SAFE_PREVIEW")
+	}
+	if !previewEnabled([]string{"apply", "-f", "deployment.yaml", "--preview"}) {
+		t.Error("expected --preview flag to enable preview")
+	}
+
+	t.Setenv("KUBECTL_SAFE_PREVIEW", "1")
+	if !previewEnabled([]string{"apply", "-f", "deployment.yaml"}) {
+		t.Error("expected KUBECTL_SAFE_PREVIEW=1 to enable preview")
+	}
+}
+
+func TestIsPreviewableCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"apply", "-f", "deployment.yaml"}, true},
+		{[]string{"delete", "pod", "mypod"}, true},
+		{[]string{"scale", "deployment/web", "--replicas=3"}, false},
+		{[]string{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isPreviewableCommand(tt.args); got != tt.expected {
+			t.Errorf("isPreviewableCommand(%v) = %v, want %v", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestPositionalArgs(t *testing.T) {
+	args := []string{"delete", "pod", "mypod", "--context", "prod", "--namespace=default"}
+	got := positionalArgs(args)
+	want := []string{"pod", "mypod"}
+
+	if len(got) != len(want) {
+		t.Fatalf("positionalArgs(%v) = %v, want %v", args, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("positionalArgs(%v)[%d] = %q, want %q", args, i, got[i], want[i])
+		}
+	}
+}
+
+func TestPositionalArgsNoneForFileApply(t *testing.T) {
+	args := []string{"apply", "-f", "deployment.yaml", "--context", "prod"}
+	if got := positionalArgs(args); len(got) != 0 {
+		t.Errorf("positionalArgs(%v) = %v, want none", args, got)
+	}
+}
+
+func TestPrintFieldDiffDetectsChanges(t *testing.T) {
+	current := []byte(`
+metadata:
+  name: web
+spec:
+  replicas: 1
+  removedField: gone
+`)
+	proposed := []byte(`
+metadata:
+  name: web
+spec:
+  replicas: 3
+  addedField: new
+`)
+
+	// printFieldDiff only prints to stdout; we're just checking it runs
+	// cleanly over a realistic diff without erroring.
+	if err := printFieldDiff(current, proposed); err != nil {
+		t.Fatalf("printFieldDiff() error = %v", err)
+	}
+}
+
+func TestPrintFieldDiffInvalidYAML(t *testing.T) {
+	if err := printFieldDiff([]byte("not: [valid yaml"), []byte("foo: bar")); err == nil {
+		t.Fatal("expected an error for invalid current YAML")
+	}
+}
@@ -0,0 +1,153 @@
+package safe
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+)
+
+// defaultPromptTemplate renders the context and, when set, the namespace.
+const defaultPromptTemplate = "{{.Context}}{{if .Namespace}} :: {{.Namespace}}{{end}}"
+
+// powerlineSeparator is the glyph used between segments in --format=powerline
+// output (requires a Nerd Font / powerline-patched font to render).
+const powerlineSeparator = ""
+
+// PromptData is the data available to the `prompt --template` flag and the
+// json/plain/powerline formats of `kubectl safe prompt`.
+type PromptData struct {
+	Context     string `json:"context"`
+	Namespace   string `json:"namespace"`
+	Cluster     string `json:"cluster"`
+	User        string `json:"user"`
+	IsProtected bool   `json:"isProtected"`
+}
+
+// kubeconfigView mirrors the subset of `kubectl config view -o json` we need.
+type kubeconfigView struct {
+	CurrentContext string `json:"current-context"`
+	Contexts       []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster   string `json:"cluster"`
+			User      string `json:"user"`
+			Namespace string `json:"namespace"`
+		} `json:"context"`
+	} `json:"contexts"`
+}
+
+// runPrompt implements `kubectl safe prompt`, a read-only subcommand that
+// prints the active context/namespace for embedding in a shell prompt
+// (oh-my-posh, starship, tmux, ...) without shelling out to kubectl twice.
+func runPrompt(args []string) error {
+	fs := flag.NewFlagSet("prompt", flag.ContinueOnError)
+	tmpl := fs.String("template", defaultPromptTemplate, "Go text/template rendered with the prompt data (used by --format=plain)")
+	format := fs.String("format", "plain", "output format: plain, json, or powerline")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	policy, err := loadProtectionPolicy()
+	if err != nil {
+		return err
+	}
+
+	data, err := fetchPromptData(policy)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		return printPromptJSON(os.Stdout, data)
+	case "powerline":
+		return printPromptPowerline(os.Stdout, data)
+	case "plain":
+		return printPromptTemplate(os.Stdout, *tmpl, data)
+	default:
+		return fmt.Errorf("unknown --format %q: must be plain, json, or powerline", *format)
+	}
+}
+
+// fetchPromptData shells out to `kubectl config view --minify -o json` and
+// parses the active context out of it. --minify trims the output down to
+// just the current context, cluster and user, so no extra dependency is
+// needed to read kubeconfig directly.
+func fetchPromptData(policy *ProtectionPolicy) (*PromptData, error) {
+	cmd := exec.Command("kubectl", "config", "view", "--minify", "-o", "json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run kubectl config view: %w", err)
+	}
+
+	return parsePromptData(output, policy)
+}
+
+// parsePromptData parses the JSON produced by `kubectl config view -o json`
+// into PromptData. Split out from fetchPromptData so it can be exercised
+// without shelling out to a real kubectl.
+func parsePromptData(output []byte, policy *ProtectionPolicy) (*PromptData, error) {
+	var view kubeconfigView
+	if err := json.Unmarshal(output, &view); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl config view output: %w", err)
+	}
+
+	data := &PromptData{Context: view.CurrentContext}
+	for _, ctx := range view.Contexts {
+		if ctx.Name == view.CurrentContext {
+			data.Cluster = ctx.Context.Cluster
+			data.User = ctx.Context.User
+			data.Namespace = ctx.Context.Namespace
+			break
+		}
+	}
+	data.IsProtected = isProtectedContext(policy, data.Context)
+
+	return data, nil
+}
+
+// printPromptTemplate renders tmpl against data, matching the semantics of
+// the --template flag.
+func printPromptTemplate(w io.Writer, tmpl string, data *PromptData) error {
+	t, err := template.New("prompt").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid --template: %w", err)
+	}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render --template: %w", err)
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// printPromptJSON writes data as a single line of JSON.
+func printPromptJSON(w io.Writer, data *PromptData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt data: %w", err)
+	}
+	fmt.Fprintln(w, string(encoded))
+	return nil
+}
+
+// printPromptPowerline renders context/namespace as powerline segments,
+// flagging a protected context with a warning glyph.
+func printPromptPowerline(w io.Writer, data *PromptData) error {
+	context := data.Context
+	if data.IsProtected {
+		context = "⚠ " + context
+	}
+
+	segments := []string{context}
+	if data.Namespace != "" {
+		segments = append(segments, data.Namespace)
+	}
+
+	fmt.Fprintln(w, strings.Join(segments, fmt.Sprintf(" %s ", powerlineSeparator)))
+	return nil
+}
@@ -0,0 +1,111 @@
+package safe
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sampleKubeconfigView = `{
+	"kind": "Config",
+	"apiVersion": "v1",
+	"current-context": "prod-east",
+	"contexts": [
+		{
+			"name": "prod-east",
+			"context": {
+				"cluster": "east-cluster",
+				"user": "east-user",
+				"namespace": "payments"
+			}
+		}
+	]
+}`
+
+func TestParsePromptData(t *testing.T) {
+	policy := defaultProtectionPolicy()
+
+	data, err := parsePromptData([]byte(sampleKubeconfigView), policy)
+	if err != nil {
+		t.Fatalf("parsePromptData() error = %v", err)
+	}
+
+	if data.Context != "prod-east" {
+		t.Errorf("Context = %q, want %q", data.Context, "prod-east")
+	}
+	if data.Namespace != "payments" {
+		t.Errorf("Namespace = %q, want %q", data.Namespace, "payments")
+	}
+	if data.Cluster != "east-cluster" {
+		t.Errorf("Cluster = %q, want %q", data.Cluster, "east-cluster")
+	}
+	if data.User != "east-user" {
+		t.Errorf("User = %q, want %q", data.User, "east-user")
+	}
+	if !data.IsProtected {
+		t.Error("expected prod-east to be reported as protected")
+	}
+}
+
+func TestParsePromptDataInvalidJSON(t *testing.T) {
+	if _, err := parsePromptData([]byte("not json"), defaultProtectionPolicy()); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestPrintPromptTemplate(t *testing.T) {
+	data := &PromptData{Context: "staging", Namespace: "web"}
+
+	var buf bytes.Buffer
+	if err := printPromptTemplate(&buf, defaultPromptTemplate, data); err != nil {
+		t.Fatalf("printPromptTemplate() error = %v", err)
+	}
+
+	if got, want := strings.TrimSpace(buf.String()), "staging :: web"; got != want {
+		t.Errorf("printPromptTemplate() output = %q, want %q", got, want)
+	}
+}
+
+func TestPrintPromptTemplateNoNamespace(t *testing.T) {
+	data := &PromptData{Context: "staging"}
+
+	var buf bytes.Buffer
+	if err := printPromptTemplate(&buf, defaultPromptTemplate, data); err != nil {
+		t.Fatalf("printPromptTemplate() error = %v", err)
+	}
+
+	if got, want := strings.TrimSpace(buf.String()), "staging"; got != want {
+		t.Errorf("printPromptTemplate() output = %q, want %q", got, want)
+	}
+}
+
+func TestPrintPromptJSON(t *testing.T) {
+	data := &PromptData{Context: "prod", Namespace: "default", IsProtected: true}
+
+	var buf bytes.Buffer
+	if err := printPromptJSON(&buf, data); err != nil {
+		t.Fatalf("printPromptJSON() error = %v", err)
+	}
+
+	var decoded PromptData
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode printPromptJSON() output: %v", err)
+	}
+	if decoded != *data {
+		t.Errorf("printPromptJSON() round-trip = %+v, want %+v", decoded, *data)
+	}
+}
+
+func TestPrintPromptPowerlineFlagsProtectedContext(t *testing.T) {
+	data := &PromptData{Context: "prod", Namespace: "default", IsProtected: true}
+
+	var buf bytes.Buffer
+	if err := printPromptPowerline(&buf, data); err != nil {
+		t.Fatalf("printPromptPowerline() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "⚠") {
+		t.Errorf("printPromptPowerline() output = %q, want warning glyph for protected context", buf.String())
+	}
+}
@@ -25,32 +25,53 @@ var DangerousCommands = []string{
 	"taint",
 }
 
-// Execute is the main entry point for the kubectl-safe plugin
+// Execute is the main entry point for the kubectl-safe plugin. It builds the
+// guard/prompt/audit/policy/dry-run/plugin subcommand tree and dispatches
+// os.Args into it; a first argument that isn't one of those subcommands
+// (the common case - a bare kubectl verb like "delete") falls through to the
+// guarded dispatch, preserving the plugin's original `kubectl safe <verb>`
+// usage.
 func Execute() error {
-	args := os.Args[1:] // Skip the program name
-
-	if len(args) == 0 {
-		return showUsage()
+	policy, err := loadProtectionPolicy()
+	if err != nil {
+		return err
 	}
 
-	// Check if this is a dangerous command
-	if !isDangerousCommand(args) {
-		// For safe commands, just pass through to kubectl
-		return executeKubectl(args)
+	root := newRootCommand(policy)
+	root.SetArgs(os.Args[1:])
+	return root.Execute()
+}
+
+// executeDangerousCommand runs the guarded flow for a dangerous kubectl
+// command - required-flag validation, confirmation, plugin veto checks, then
+// execution - and records the outcome to the audit log regardless of which
+// step it stopped at.
+func executeDangerousCommand(args []string, policy *ProtectionPolicy) error {
+	start := auditClock.Now()
+	context := extractFlagValue(args, "--context", "-c")
+	namespace := extractFlagValue(args, "--namespace", "-n")
+
+	audit := func(decision string, exitCode int, result error) error {
+		recordAudit(args, context, namespace, decision, exitCode, auditClock.Now().Sub(start))
+		return result
 	}
 
-	// For dangerous commands, enforce safety checks
 	if err := validateRequiredFlags(args); err != nil {
-		return err
+		return audit(auditBlocked, 1, err)
 	}
 
 	// Show interactive confirmation
-	if err := showConfirmation(args); err != nil {
-		return err
+	if err := showConfirmation(args, policy); err != nil {
+		return audit(auditCancelled, 1, err)
+	}
+
+	if err := runGuardPlugins(policy, args, context, namespace); err != nil {
+		return audit(auditBlocked, 1, err)
 	}
 
 	// Execute the kubectl command
-	return executeKubectl(args)
+	err := executeKubectl(stripKubectlSafeFlags(args))
+	return audit(auditAllowed, exitCodeFromError(err), err)
 }
 
 // isDangerousCommand checks if the command contains dangerous operations
@@ -119,23 +140,51 @@ func validateRequiredFlags(args []string) error {
 	return nil
 }
 
-// showConfirmation displays an interactive prompt for dangerous commands
-func showConfirmation(args []string) error {
+// showConfirmation displays an interactive prompt for dangerous commands.
+// Against a context matched by policy's protected patterns (or a command
+// with a "typed" override) this escalates to a typed-confirmation flow that
+// requires the user to retype the context name.
+func showConfirmation(args []string, policy *ProtectionPolicy) error {
 	fmt.Printf("⚠️  DANGEROUS COMMAND DETECTED ⚠️\n\n")
 	fmt.Printf("You are about to execute: kubectl %s\n\n", strings.Join(args, " "))
-	
+
 	// Extract context and namespace for display
 	context := extractFlagValue(args, "--context", "-c")
 	namespace := extractFlagValue(args, "--namespace", "-n")
-	
+
 	fmt.Printf("Target Details:\n")
 	fmt.Printf("  Context:   %s\n", context)
 	fmt.Printf("  Namespace: %s\n\n", namespace)
-	
+
+	if previewEnabled(args) && isPreviewableCommand(args) {
+		if err := showPreview(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: dry-run preview failed: %v\n", err)
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	if needsBlastRadiusCheck(args) {
+		report, err := BlastRadius(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: blast radius analysis failed: %v\n", err)
+		} else if err := confirmBlastRadius(reader, report, maxSilentTargets(args)); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("This operation may cause data loss or service disruption.\n")
+
+	if confirmationMode(policy, args[0], context) == confirmationTyped {
+		return requireTypedConfirmation(reader, context, namespace, policy.RequireTypedNamespace)
+	}
+	return requireSimpleConfirmation(reader)
+}
+
+// requireSimpleConfirmation asks for a plain yes/no before proceeding.
+func requireSimpleConfirmation(reader *bufio.Reader) error {
 	fmt.Printf("Are you sure you want to continue? (yes/no): ")
 
-	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
 	if err != nil {
 		return fmt.Errorf("failed to read user input: %w", err)
@@ -151,6 +200,55 @@ func showConfirmation(args []string) error {
 	return nil
 }
 
+// requireTypedConfirmation forces the user to retype the context (and, when
+// requireNamespace is set, the namespace) exactly before proceeding. It
+// refuses outright when stdin isn't an interactive terminal, since a
+// scripted or piped confirmation would defeat the point of a typed guard.
+func requireTypedConfirmation(reader *bufio.Reader, context, namespace string, requireNamespace bool) error {
+	if !isInteractiveStdin() {
+		return fmt.Errorf("refusing to run a protected command: typed confirmation requires an interactive terminal")
+	}
+
+	fmt.Printf("🛑 This command requires typed confirmation.\n")
+	fmt.Printf("To proceed, type the context name (%q) and press Enter: ", context)
+
+	typedContext, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read user input: %w", err)
+	}
+	if strings.TrimSpace(typedContext) != context {
+		fmt.Println("Operation cancelled: context name did not match.")
+		return fmt.Errorf("operation cancelled: typed context did not match")
+	}
+
+	if requireNamespace {
+		fmt.Printf("Type the namespace name (%q) and press Enter: ", namespace)
+		typedNamespace, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read user input: %w", err)
+		}
+		if strings.TrimSpace(typedNamespace) != namespace {
+			fmt.Println("Operation cancelled: namespace name did not match.")
+			return fmt.Errorf("operation cancelled: typed namespace did not match")
+		}
+	}
+
+	fmt.Println("Proceeding with operation...")
+	return nil
+}
+
+// isInteractiveStdin reports whether stdin looks like a terminal rather than
+// a pipe or redirected file. It's a var, not a plain func, so tests can swap
+// it out to reach past requireTypedConfirmation's non-TTY rejection and
+// exercise the retype logic itself.
+var isInteractiveStdin = func() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // extractFlagValue extracts the value for a given flag from args
 func extractFlagValue(args []string, longFlag, shortFlag string) string {
 	for i, arg := range args {
@@ -171,12 +269,7 @@ func extractFlagValue(args []string, longFlag, shortFlag string) string {
 
 // executeKubectl runs the actual kubectl command
 func executeKubectl(args []string) error {
-	cmd := exec.Command("kubectl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	
-	return cmd.Run()
+	return kubectlRunner.Run(args)
 }
 
 // getKubeconfigContexts returns the list of available contexts from kubeconfig
@@ -228,6 +321,28 @@ Dangerous commands that trigger safety checks:
 
 For safe commands, this plugin acts as a transparent pass-through to kubectl.
 
+kubectl safe prompt prints the active context/namespace (read-only, no
+confirmation) for embedding in a shell prompt:
+  kubectl safe prompt --format=powerline
+
+Every dangerous command is recorded to an audit log. kubectl safe audit
+tails and filters it:
+  kubectl safe audit --context=prod --decision=cancelled
+
+Pass --preview (or set KUBECTL_SAFE_PREVIEW=1) on apply/patch/replace/create/
+delete to render a server-side dry-run preview before confirming.
+
+A broad delete (-l/--selector or --all), scale --replicas=0, drain, or
+rollout restart first shows a blast radius: the count and names of affected
+resources. Past --max-silent-targets (default 5) you must type the count
+to proceed.
+
+Additional subcommands:
+  kubectl safe guard <kubectl-command>   explicit spelling of the default guard
+  kubectl safe policy                  print the effective protection policy
+  kubectl safe dry-run <kubectl-command>  run with --dry-run=client, no confirmation
+  kubectl safe plugin [name] [args...] list or invoke kubectl-safe-* plugins
+
 `, strings.Join(DangerousCommands, ", "))
 	return nil
 }
\ No newline at end of file